@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func registerAuthFlags(cmd *cobra.Command) {
+	cmd.Flags().String("auth-basic", "", "Sends the provided user:pass as an HTTP Basic authorization header")
+	cmd.Flags().String("auth-bearer", "", "Sends the provided token as an HTTP Bearer authorization header")
+	cmd.Flags().StringArray("auth-header", nil, "Sends an additional request header in the form name:value; may be repeated")
+	cmd.Flags().String("auth-cf-access", "", "Sends a Cloudflare Access service token, read from an env var, a file, or \"-\" for stdin")
+	cmd.Flags().String("cf-jump-destination", "", "Sets the Cf-Access-Jump-Destination header; only used together with --auth-cf-access")
+}
+
+// applyAuthFlags sets authentication related headers on req based on the
+// --auth-* flags.
+func applyAuthFlags(cmd *cobra.Command, req *http.Request) {
+	if v, _ := cmd.Flags().GetString("auth-basic"); v != "" {
+		user, pass, ok := strings.Cut(v, ":")
+		if !ok {
+			die(91, "Invalid value for --auth-basic flag: %q, want user:pass", v)
+		}
+		req.SetBasicAuth(user, pass)
+	}
+
+	if v, _ := cmd.Flags().GetString("auth-bearer"); v != "" {
+		req.Header.Set("Authorization", "Bearer "+v)
+	}
+
+	hs, _ := cmd.Flags().GetStringArray("auth-header")
+	for _, h := range hs {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok {
+			die(91, "Invalid value for --auth-header flag: %q, want name:value", h)
+		}
+		req.Header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	if v, _ := cmd.Flags().GetString("auth-cf-access"); v != "" {
+		token, err := readAuthToken(v)
+		if err != nil {
+			die(91, "Cannot read --auth-cf-access token: %s", err)
+		}
+		req.Header.Set("Cf-Access-Token", token)
+
+		if dst, _ := cmd.Flags().GetString("cf-jump-destination"); dst != "" {
+			req.Header.Set("Cf-Access-Jump-Destination", dst)
+		}
+	}
+}
+
+// readAuthToken resolves a token source, which may be the name of an
+// environment variable, a path to a file, or "-" to read from stdin.
+func readAuthToken(src string) (string, error) {
+	if src == "-" {
+		b, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(b)), nil
+	}
+
+	if v, ok := os.LookupEnv(src); ok {
+		return v, nil
+	}
+
+	b, err := os.ReadFile(src)
+	if err != nil {
+		return "", fmt.Errorf("not an env var and cannot read as file: %w", err)
+	}
+	return strings.TrimSpace(string(b)), nil
+}
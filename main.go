@@ -1,18 +1,17 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"errors"
+	"encoding/json"
 	"fmt"
 	"io"
-	"net"
 	"net/http"
 	"os"
 	"strings"
-	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/PlanitarInc/http-assert/pkg/httpassert"
 )
 
 func main() {
@@ -21,7 +20,14 @@ func main() {
 		Short: "Perform HTTP request and assert received HTTP response",
 		Args:  cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			httpClient := getHttpClient(parseHostMappings(cmd))
+			if ws, _ := cmd.Flags().GetBool("websocket"); ws {
+				runWebSocket(cmd, args[0])
+				return
+			}
+
+			maxRedirects, _ := cmd.Flags().GetInt("follow-redirects")
+			var redirectChain []string
+			httpClient := httpassert.GetHTTPClient(parseHostMappings(cmd), maxRedirects, &redirectChain)
 
 			m, _ := cmd.Flags().GetString("request")
 			b := io.Reader(http.NoBody)
@@ -32,9 +38,26 @@ func main() {
 			if err != nil {
 				die(91, "Cannot create %s request: %s", m, err)
 			}
+			applyAuthFlags(cmd, req)
 
-			if err := assertRequest(httpClient, req, parseAssertionFlags(cmd)...); err != nil {
-				die(93, "Cannot create %s request: %s", m, err)
+			runner := &httpassert.Runner{
+				Client:        httpClient,
+				Retry:         parseRetryFlags(cmd),
+				RedirectChain: &redirectChain,
+			}
+
+			assertions := parseAssertionFlags(cmd)
+			res, err := runner.Do(cmd.Context(), req, assertions...)
+			writeOutputFiles(cmd, res, assertions)
+			if err != nil {
+				var b strings.Builder
+				fmt.Fprintf(&b, "%s\n", err)
+				if res != nil {
+					b.WriteString("\n")
+					res.Dump(&b)
+					b.WriteString("\n")
+				}
+				die(93, "%s", b.String())
 			}
 		},
 	}
@@ -45,7 +68,14 @@ func main() {
 		"Specifies a custom request method to use when communicating with the HTTP server")
 	cmd.Flags().StringP("data", "d", "",
 		"Sends the specified data in a POST request to the HTTP server")
+	cmd.Flags().Int("follow-redirects", 0,
+		"Follow up to N redirects instead of stopping at the first Location header; bare flag defaults to 10")
+	cmd.Flags().Lookup("follow-redirects").NoOptDefVal = "10"
 	registerAssertionFlags(cmd)
+	registerRetryFlags(cmd)
+	registerAuthFlags(cmd)
+	registerWebSocketFlags(cmd)
+	registerOutputFlags(cmd)
 
 	if err := cmd.ExecuteContext(context.Background()); err != nil {
 		die(103, "%s", err)
@@ -60,8 +90,8 @@ func die(rc int, format string, args ...interface{}) {
 	os.Exit(rc)
 }
 
-func parseHostMappings(cmd *cobra.Command) []hostMapping {
-	var res []hostMapping
+func parseHostMappings(cmd *cobra.Command) []httpassert.HostMapping {
+	var res []httpassert.HostMapping
 
 	vals, _ := cmd.Flags().GetStringArray("maphost")
 	for _, r := range vals {
@@ -76,7 +106,7 @@ func parseHostMappings(cmd *cobra.Command) []hostMapping {
 			die(91, "Invalid value for --maphost flag: %q", r)
 		}
 
-		r := hostMapping{Src: r[:i+1+j], Dst: r[i+1+j+1:]}
+		r := httpassert.HostMapping{Src: r[:i+1+j], Dst: r[i+1+j+1:]}
 		res = append(res, r)
 	}
 
@@ -87,35 +117,49 @@ func registerAssertionFlags(cmd *cobra.Command) {
 	cmd.Flags().Int("assert-status", 0, "Assert response status equals the provided value")
 	cmd.Flags().StringArray("assert-header", nil, "Assert header equals the provided regexp")
 	cmd.Flags().StringP("assert-body", "B", "", "Assert body equals the provided value")
+	cmd.Flags().StringArray("assert-body-jq", nil,
+		"Assert the result of a jq expression against the JSON response body; use expr=<json-value> to check equality, or just expr to assert truthiness")
+	cmd.Flags().StringArray("assert-body-schema", nil,
+		"Assert the response body validates against the JSON Schema (draft-7) document at the provided file path")
 
 	// Common shorthands
 	cmd.Flags().Bool("assert-ok", false, "Assert response is successful (2xx)")
 	cmd.Flags().String("assert-redirect", "", "Assert response redirects to the provided URL")
+
+	// Timing/trace assertions
+	cmd.Flags().Duration("assert-ttfb-under", 0, "Assert time to first response byte is under the provided duration")
+	cmd.Flags().Duration("assert-total-under", 0, "Assert total response time is under the provided duration")
+	cmd.Flags().String("assert-tls-version", "", "Assert negotiated TLS version equals the provided value (1.2 or 1.3)")
+
+	// Redirect chain assertions (require --follow-redirects)
+	cmd.Flags().String("assert-redirect-chain", "",
+		"Assert the ordered sequence of redirect hop URLs; comma-separated list of exact URLs or /regexp/ patterns")
+	cmd.Flags().String("assert-final-url", "", "Assert the URL reached at the end of the redirect chain")
 }
 
-func parseAssertionFlags(cmd *cobra.Command) []Assertion {
-	var res []Assertion
+func parseAssertionFlags(cmd *cobra.Command) []httpassert.Assertion {
+	var res []httpassert.Assertion
 
 	if cmd.Flags().Changed("assert-ok") {
 		if v, _ := cmd.Flags().GetBool("assert-ok"); v {
-			res = append(res, AssertStatusOK())
+			res = append(res, httpassert.AssertStatusOK())
 		} else {
-			res = append(res, AssertStatusNOK())
+			res = append(res, httpassert.AssertStatusNOK())
 		}
 	}
 
 	if cmd.Flags().Changed("assert-redirect") {
 		v, _ := cmd.Flags().GetString("assert-redirect")
 		if strings.HasPrefix(v, "=") {
-			res = append(res, AssertRedirectEqual(v[1:]))
+			res = append(res, httpassert.AssertRedirectEqual(v[1:]))
 		} else {
-			res = append(res, AssertRedirectMatch(v))
+			res = append(res, httpassert.AssertRedirectMatch(v))
 		}
 	}
 
 	if cmd.Flags().Changed("assert-status") {
 		s, _ := cmd.Flags().GetInt("assert-status")
-		res = append(res, AssertStatusEqual(s))
+		res = append(res, httpassert.AssertStatusEqual(s))
 	}
 
 	hs, _ := cmd.Flags().GetStringArray("assert-header")
@@ -134,15 +178,15 @@ func parseAssertionFlags(cmd *cobra.Command) []Assertion {
 
 		if exactMatch {
 			if value == "" {
-				res = append(res, AssertHeaderPresent(name))
+				res = append(res, httpassert.AssertHeaderPresent(name))
 			} else {
-				res = append(res, AssertHeaderEqual(name, value))
+				res = append(res, httpassert.AssertHeaderEqual(name, value))
 			}
 		} else {
 			if value == "" {
-				res = append(res, AssertHeaderPresent(name))
+				res = append(res, httpassert.AssertHeaderPresent(name))
 			} else {
-				res = append(res, AssertHeaderMatch(name, value))
+				res = append(res, httpassert.AssertHeaderMatch(name, value))
 			}
 		}
 	}
@@ -150,122 +194,78 @@ func parseAssertionFlags(cmd *cobra.Command) []Assertion {
 	if cmd.Flags().Changed("assert-body") {
 		v, _ := cmd.Flags().GetString("assert-body")
 		if strings.HasPrefix(v, "=") {
-			res = append(res, AssertBodyEqual(v[1:]))
+			res = append(res, httpassert.AssertBodyEqual(v[1:]))
 		} else {
-			res = append(res, AssertBodyMatch(v))
+			res = append(res, httpassert.AssertBodyMatch(v))
 		}
 	}
 
-	return res
-}
-
-func assertRequest(httpClient *http.Client, req *http.Request, assertions ...Assertion) error {
-	if len(assertions) == 0 {
-		return fmt.Errorf("no assertions defined")
+	jqs, _ := cmd.Flags().GetStringArray("assert-body-jq")
+	for _, j := range jqs {
+		expr := j
+		var expected interface{}
+		var hasExpected bool
+		if i := jqExpectedSplit(j); i >= 0 {
+			expr = j[:i]
+			hasExpected = true
+			if err := json.Unmarshal([]byte(j[i+1:]), &expected); err != nil {
+				die(91, "Invalid value for --assert-body-jq flag: %q: %s", j, err)
+			}
+		}
+		res = append(res, httpassert.AssertBodyJQ(expr, expected, hasExpected))
 	}
 
-	res, err := httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
+	schemas, _ := cmd.Flags().GetStringArray("assert-body-schema")
+	for _, s := range schemas {
+		res = append(res, httpassert.AssertBodyJSONSchema(s))
 	}
-	defer res.Body.Close()
 
-	httpRes := &httpResponse{Response: res}
-	httpRes.BodyBytes, _ = io.ReadAll(res.Body)
-
-	var assertErrors []error
-	for i := range assertions {
-		if err := assertions[i](httpRes); err != nil {
-			assertErrors = append(assertErrors, err)
-		}
-	}
-	if len(assertErrors) > 0 {
-		var b strings.Builder
-		fmt.Fprintf(&b, "%d assertions failed:\n", len(assertErrors))
-		for i := range assertErrors {
-			fmt.Fprintf(&b, "- %s\n", assertErrors[i])
-		}
-		b.WriteString("\n\n")
-		httpRes.writeTo(&b, true)
-		b.WriteString("\n")
-		return errors.New(b.String())
+	if cmd.Flags().Changed("assert-ttfb-under") {
+		d, _ := cmd.Flags().GetDuration("assert-ttfb-under")
+		res = append(res, httpassert.AssertTTFBUnder(d))
 	}
 
-	return nil
-}
-
-type httpResponse struct {
-	*http.Response
-	BodyBytes []byte
-}
-
-func (r httpResponse) writeTo(w io.Writer, withBody bool) {
-	// Ensure to close previous body
-	b := r.Response.Body
-	defer b.Close()
-	if withBody {
-		r.Response.Body = io.NopCloser(bytes.NewReader(r.BodyBytes))
-	} else {
-		r.Response.Body = io.NopCloser(strings.NewReader("<<Payload is omitted>>"))
+	if cmd.Flags().Changed("assert-total-under") {
+		d, _ := cmd.Flags().GetDuration("assert-total-under")
+		res = append(res, httpassert.AssertTotalUnder(d))
 	}
-	r.Response.Write(w)
-}
 
-func getHttpClient(hostMappings []hostMapping) *http.Client {
-	dialer := &net.Dialer{
-		Timeout:   10 * time.Second,
-		KeepAlive: 20 * time.Second,
+	if cmd.Flags().Changed("assert-tls-version") {
+		v, _ := cmd.Flags().GetString("assert-tls-version")
+		res = append(res, httpassert.AssertTLSVersion(v))
 	}
 
-	return &http.Client{
-		Timeout: 20 * time.Second,
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			// Disallow redirects
-			return http.ErrUseLastResponse
-		},
-		Transport: &http.Transport{
-			ForceAttemptHTTP2:     false,
-			MaxIdleConns:          10,
-			IdleConnTimeout:       20 * time.Second,
-			TLSHandshakeTimeout:   10 * time.Second,
-			ExpectContinueTimeout: 1 * time.Second,
-			Proxy:                 http.ProxyFromEnvironment,
-			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
-				for _, r := range hostMappings {
-					if r.Matches(addr) {
-						addr = r.DstHost()
-						break
-					}
-				}
-				return dialer.DialContext(ctx, network, addr)
-			},
-		},
+	if cmd.Flags().Changed("assert-redirect-chain") {
+		v, _ := cmd.Flags().GetString("assert-redirect-chain")
+		res = append(res, httpassert.AssertRedirectChain(strings.Split(v, ",")))
 	}
-}
 
-type hostMapping struct {
-	// Src is the source host in the form of `hostname:port`.
-	Src string
-	// Dst is the destination host in the form of either `hostname:port` or just
-	// `hostname`. If just the hostname is specified without a port then the
-	// source port will be used.
-	Dst string
-}
+	if cmd.Flags().Changed("assert-final-url") {
+		v, _ := cmd.Flags().GetString("assert-final-url")
+		res = append(res, httpassert.AssertFinalURL(v))
+	}
 
-func (r hostMapping) Matches(addr string) bool {
-	return r.Src == addr
+	return res
 }
 
-func (r hostMapping) DstHost() string {
-	// Dst already has a port
-	if idx := strings.Index(r.Dst, ":"); idx >= 0 {
-		return r.Dst
-	}
-
-	// Use the source port
-	var port string
-	if idx := strings.Index(r.Src, ":"); idx >= 0 {
-		port = r.Src[idx:]
+// jqExpectedSplit returns the index of the "=" separating a --assert-body-jq
+// filter from its trailing `=<json>` expected value, or -1 if j carries no
+// expected value. It skips over "=" characters that are part of a jq
+// comparison operator (==, !=, >=, <=) so that filters like `.status ==
+// "ok"` are not mistaken for `expr=expected`.
+func jqExpectedSplit(j string) int {
+	for i := 0; i < len(j); i++ {
+		if j[i] != '=' {
+			continue
+		}
+		if i+1 < len(j) && j[i+1] == '=' {
+			i++ // skip the second '=' of "=="
+			continue
+		}
+		if i > 0 && strings.ContainsRune("!<>=", rune(j[i-1])) {
+			continue
+		}
+		return i
 	}
-	return r.Dst + port
+	return -1
 }
@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestJqExpectedSplit(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want int
+	}{
+		{name: "no expected value", in: ".status", want: -1},
+		{name: "equality operator is not a separator", in: `.status == "ok"`, want: -1},
+		{name: "inequality operator is not a separator", in: `.status != "ok"`, want: -1},
+		{name: "gte operator is not a separator", in: ".count >= 1", want: -1},
+		{name: "lte operator is not a separator", in: ".count <= 1", want: -1},
+		{name: "trailing expected value", in: `.status="ok"`, want: 7},
+		{name: "expected value after comparison filter", in: `.status == "ok"=true`, want: 15},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jqExpectedSplit(tt.in); got != tt.want {
+				t.Fatalf("jqExpectedSplit(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PlanitarInc/http-assert/pkg/httpassert"
+)
+
+func registerOutputFlags(cmd *cobra.Command) {
+	cmd.Flags().String("output-har", "", "Write the request/response (with timings) as an HTTP Archive (HAR) 1.2 document to the provided file")
+	cmd.Flags().String("output-junit", "", "Write a JUnit XML report, one <testcase> per assertion, to the provided file")
+}
+
+// writeOutputFiles serializes res to the --output-har and --output-junit
+// files, if requested. It runs whether the assertions passed or failed.
+func writeOutputFiles(cmd *cobra.Command, res *httpassert.Response, assertions []httpassert.Assertion) {
+	if res == nil {
+		return
+	}
+
+	if path, _ := cmd.Flags().GetString("output-har"); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			die(91, "Cannot create --output-har file: %s", err)
+		}
+		err = httpassert.WriteHAR(f, res)
+		f.Close()
+		if err != nil {
+			die(91, "Cannot write --output-har file: %s", err)
+		}
+	}
+
+	if path, _ := cmd.Flags().GetString("output-junit"); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			die(91, "Cannot create --output-junit file: %s", err)
+		}
+		results := httpassert.EvaluateAssertions(res, assertions)
+		err = httpassert.WriteJUnit(f, res, results)
+		f.Close()
+		if err != nil {
+			die(91, "Cannot write --output-junit file: %s", err)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package httpassert
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// AssertStatusOK asserts that the response status is successful (2xx).
+func AssertStatusOK() Assertion {
+	return func(r *Response) error {
+		if r.StatusCode < 200 || r.StatusCode >= 300 {
+			return fmt.Errorf("status %d is not successful (2xx)", r.StatusCode)
+		}
+		return nil
+	}
+}
+
+// AssertStatusNOK asserts that the response status is not successful (2xx).
+func AssertStatusNOK() Assertion {
+	return func(r *Response) error {
+		if r.StatusCode >= 200 && r.StatusCode < 300 {
+			return fmt.Errorf("status %d is successful (2xx)", r.StatusCode)
+		}
+		return nil
+	}
+}
+
+// AssertStatusEqual asserts that the response status equals want.
+func AssertStatusEqual(want int) Assertion {
+	return func(r *Response) error {
+		if r.StatusCode != want {
+			return fmt.Errorf("status %d does not equal %d", r.StatusCode, want)
+		}
+		return nil
+	}
+}
+
+// AssertRedirectEqual asserts that the response's Location header equals want.
+func AssertRedirectEqual(want string) Assertion {
+	return func(r *Response) error {
+		got := r.Header.Get("Location")
+		if got != want {
+			return fmt.Errorf("Location header %q does not equal %q", got, want)
+		}
+		return nil
+	}
+}
+
+// AssertRedirectMatch asserts that the response's Location header matches
+// the provided regexp.
+func AssertRedirectMatch(pattern string) Assertion {
+	re, err := regexp.Compile(pattern)
+	return func(r *Response) error {
+		if err != nil {
+			return fmt.Errorf("invalid regexp %q: %s", pattern, err)
+		}
+		got := r.Header.Get("Location")
+		if !re.MatchString(got) {
+			return fmt.Errorf("Location header %q does not match %s", got, pattern)
+		}
+		return nil
+	}
+}
+
+// AssertHeaderPresent asserts that the response has a non-empty header
+// named name.
+func AssertHeaderPresent(name string) Assertion {
+	return func(r *Response) error {
+		if r.Header.Get(name) == "" {
+			return fmt.Errorf("header %q is not present", name)
+		}
+		return nil
+	}
+}
+
+// AssertHeaderEqual asserts that the response header name equals want.
+func AssertHeaderEqual(name, want string) Assertion {
+	return func(r *Response) error {
+		got := r.Header.Get(name)
+		if got != want {
+			return fmt.Errorf("header %q value %q does not equal %q", name, got, want)
+		}
+		return nil
+	}
+}
+
+// AssertHeaderMatch asserts that the response header name matches the
+// provided regexp.
+func AssertHeaderMatch(name, pattern string) Assertion {
+	re, err := regexp.Compile(pattern)
+	return func(r *Response) error {
+		if err != nil {
+			return fmt.Errorf("invalid regexp %q: %s", pattern, err)
+		}
+		got := r.Header.Get(name)
+		if !re.MatchString(got) {
+			return fmt.Errorf("header %q value %q does not match %s", name, got, pattern)
+		}
+		return nil
+	}
+}
+
+// AssertBodyEqual asserts that the response body equals want.
+func AssertBodyEqual(want string) Assertion {
+	return func(r *Response) error {
+		got := string(r.BodyBytes)
+		if got != want {
+			return fmt.Errorf("body %q does not equal %q", got, want)
+		}
+		return nil
+	}
+}
+
+// AssertBodyMatch asserts that the response body matches the provided
+// regexp.
+func AssertBodyMatch(pattern string) Assertion {
+	re, err := regexp.Compile(pattern)
+	return func(r *Response) error {
+		if err != nil {
+			return fmt.Errorf("invalid regexp %q: %s", pattern, err)
+		}
+		if !re.Match(r.BodyBytes) {
+			return fmt.Errorf("body %q does not match %s", r.BodyBytes, pattern)
+		}
+		return nil
+	}
+}
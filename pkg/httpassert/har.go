@@ -0,0 +1,139 @@
+package httpassert
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+)
+
+type harDocument struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	HTTPVersion string      `json:"httpVersion"`
+	Headers     []harHeader `json:"headers"`
+	BodySize    int         `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Headers     []harHeader `json:"headers"`
+	Content     harContent  `json:"content"`
+	BodySize    int         `json:"bodySize"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harTimings struct {
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	SSL     float64 `json:"ssl"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// WriteHAR serializes res (request, response, and the timings captured by
+// the httptrace subsystem) as a single-entry HTTP Archive (HAR) 1.2
+// document.
+func WriteHAR(w io.Writer, res *Response) error {
+	entry := harEntry{
+		StartedDateTime: time.Now().UTC().Format(time.RFC3339),
+		Request:         harRequestFrom(res.Request),
+		Response:        harResponseFrom(res),
+	}
+	if res.Timing != nil {
+		entry.Time = harMillis(res.Timing.Total)
+		entry.Timings = harTimings{
+			DNS:     harMillis(res.Timing.DNSDuration),
+			Connect: harMillis(res.Timing.ConnectDuration),
+			SSL:     harMillis(res.Timing.TLSDuration),
+			Wait:    harMillis(res.Timing.TTFB),
+			Receive: harMillis(res.Timing.Total - res.Timing.TTFB),
+		}
+	}
+
+	doc := harDocument{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "http-assert", Version: "1"},
+		Entries: []harEntry{entry},
+	}}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+func harMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func harRequestFrom(req *http.Request) harRequest {
+	if req == nil {
+		return harRequest{}
+	}
+	return harRequest{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		HTTPVersion: req.Proto,
+		Headers:     harHeadersFrom(req.Header),
+	}
+}
+
+func harResponseFrom(res *Response) harResponse {
+	return harResponse{
+		Status:      res.StatusCode,
+		StatusText:  http.StatusText(res.StatusCode),
+		HTTPVersion: res.Proto,
+		Headers:     harHeadersFrom(res.Header),
+		Content: harContent{
+			Size:     len(res.BodyBytes),
+			MimeType: res.Header.Get("Content-Type"),
+			Text:     string(res.BodyBytes),
+		},
+		BodySize: len(res.BodyBytes),
+	}
+}
+
+func harHeadersFrom(h http.Header) []harHeader {
+	var res []harHeader
+	for name, values := range h {
+		for _, v := range values {
+			res = append(res, harHeader{Name: name, Value: v})
+		}
+	}
+	return res
+}
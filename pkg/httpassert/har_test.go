@@ -0,0 +1,136 @@
+package httpassert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWriteHAR(t *testing.T) {
+	req := &http.Request{
+		Method: "GET",
+		URL:    &url.URL{Scheme: "http", Host: "example.com", Path: "/foo"},
+		Proto:  "HTTP/1.1",
+		Header: http.Header{"Accept": []string{"text/plain"}},
+	}
+	res := &Response{
+		Response: &http.Response{
+			StatusCode: 200,
+			Proto:      "HTTP/1.1",
+			Header:     http.Header{"Content-Type": []string{"text/plain"}},
+			Request:    req,
+		},
+		BodyBytes: []byte("hello"),
+		Timing: &requestTiming{
+			DNSDuration:     time.Millisecond,
+			ConnectDuration: 2 * time.Millisecond,
+			TLSDuration:     3 * time.Millisecond,
+			TTFB:            10 * time.Millisecond,
+			Total:           15 * time.Millisecond,
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteHAR(&buf, res); err != nil {
+		t.Fatalf("WriteHAR() error = %v", err)
+	}
+
+	var doc harDocument
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("WriteHAR() produced invalid JSON: %v", err)
+	}
+
+	if doc.Log.Version != "1.2" {
+		t.Errorf("Log.Version = %q, want %q", doc.Log.Version, "1.2")
+	}
+	if len(doc.Log.Entries) != 1 {
+		t.Fatalf("len(Log.Entries) = %d, want 1", len(doc.Log.Entries))
+	}
+
+	entry := doc.Log.Entries[0]
+	if _, err := time.Parse(time.RFC3339, entry.StartedDateTime); err != nil {
+		t.Errorf("StartedDateTime = %q is not RFC3339: %v", entry.StartedDateTime, err)
+	}
+
+	want := harEntry{
+		StartedDateTime: entry.StartedDateTime,
+		Time:            15,
+		Request: harRequest{
+			Method:      "GET",
+			URL:         "http://example.com/foo",
+			HTTPVersion: "HTTP/1.1",
+			Headers:     []harHeader{{Name: "Accept", Value: "text/plain"}},
+		},
+		Response: harResponse{
+			Status:      200,
+			StatusText:  http.StatusText(200),
+			HTTPVersion: "HTTP/1.1",
+			Headers:     []harHeader{{Name: "Content-Type", Value: "text/plain"}},
+			Content: harContent{
+				Size:     5,
+				MimeType: "text/plain",
+				Text:     "hello",
+			},
+			BodySize: 5,
+		},
+		Timings: harTimings{
+			DNS:     1,
+			Connect: 2,
+			SSL:     3,
+			Wait:    10,
+			Receive: 5,
+		},
+	}
+
+	if !reflect.DeepEqual(entry, want) {
+		t.Errorf("entry =\n%+v\nwant\n%+v", entry, want)
+	}
+}
+
+// TestWriteHARReceiveTiming guards against Timings.Receive regressing to ~0:
+// it is derived from Timing.Total minus Timing.TTFB, so it only reflects the
+// real body transfer time as long as Total covers the full body read.
+func TestWriteHARReceiveTiming(t *testing.T) {
+	const bodyDelay = 50 * time.Millisecond
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher := w.(http.Flusher)
+		w.Write([]byte("a"))
+		flusher.Flush()
+		time.Sleep(bodyDelay)
+		w.Write([]byte("b"))
+	}))
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+
+	runner := NewRunner(srv.Client())
+	res, err := runner.Do(context.Background(), req, AssertStatusOK())
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteHAR(&buf, res); err != nil {
+		t.Fatalf("WriteHAR() error = %v", err)
+	}
+
+	var doc harDocument
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("WriteHAR() produced invalid JSON: %v", err)
+	}
+
+	receive := time.Duration(doc.Log.Entries[0].Timings.Receive * float64(time.Millisecond))
+	if receive < bodyDelay {
+		t.Fatalf("Timings.Receive = %s, want >= %s", receive, bodyDelay)
+	}
+}
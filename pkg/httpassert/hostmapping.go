@@ -0,0 +1,93 @@
+package httpassert
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HostMapping overrides the destination address used to dial a specific
+// host:port pair, similarly to curl's --resolve.
+type HostMapping struct {
+	// Src is the source host in the form of `hostname:port`.
+	Src string
+	// Dst is the destination host in the form of either `hostname:port` or
+	// just `hostname`. If just the hostname is specified without a port
+	// then the source port will be used.
+	Dst string
+}
+
+func (r HostMapping) Matches(addr string) bool {
+	return r.Src == addr
+}
+
+func (r HostMapping) DstHost() string {
+	// Dst already has a port
+	if idx := strings.Index(r.Dst, ":"); idx >= 0 {
+		return r.Dst
+	}
+
+	// Use the source port
+	var port string
+	if idx := strings.Index(r.Src, ":"); idx >= 0 {
+		port = r.Src[idx:]
+	}
+	return r.Dst + port
+}
+
+// dialContext returns a DialContext func that applies hostMappings before
+// delegating to a net.Dialer with http-assert's standard timeouts. It is
+// shared by GetHTTPClient and GetWebSocketDialer so --maphost behaves the
+// same regardless of protocol.
+func dialContext(hostMappings []HostMapping) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	dialer := &net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 20 * time.Second,
+	}
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		for _, r := range hostMappings {
+			if r.Matches(addr) {
+				addr = r.DstHost()
+				break
+			}
+		}
+		return dialer.DialContext(ctx, network, addr)
+	}
+}
+
+// GetHTTPClient builds the *http.Client used to perform requests. hostMappings
+// overrides dial addresses for specific host:port pairs. maxRedirects allows
+// following up to that many redirects instead of stopping at the first
+// Location header (maxRedirects <= 0 disables following). redirectChain, if
+// non-nil, is appended to with each hop's URL as it is followed.
+func GetHTTPClient(hostMappings []HostMapping, maxRedirects int, redirectChain *[]string) *http.Client {
+	return &http.Client{
+		Timeout: 20 * time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if maxRedirects <= 0 {
+				return http.ErrUseLastResponse
+			}
+			if len(via) > maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+
+			if redirectChain != nil {
+				*redirectChain = append(*redirectChain, req.URL.String())
+			}
+			return nil
+		},
+		Transport: &http.Transport{
+			ForceAttemptHTTP2:     false,
+			MaxIdleConns:          10,
+			IdleConnTimeout:       20 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+			Proxy:                 http.ProxyFromEnvironment,
+			DialContext:           dialContext(hostMappings),
+		},
+	}
+}
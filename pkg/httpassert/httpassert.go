@@ -0,0 +1,187 @@
+// Package httpassert implements the request/response assertion engine
+// behind the http-assert CLI, so other Go programs (integration tests,
+// healthchecks) can perform the same checks in-process.
+package httpassert
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"time"
+)
+
+// Assertion checks a single property of a Response, returning a descriptive
+// error if the property does not hold.
+type Assertion func(*Response) error
+
+// Response wraps the http.Response returned by a request, along with the
+// data http-assert needs to evaluate assertions and render a failure dump.
+type Response struct {
+	*http.Response
+	BodyBytes     []byte
+	Timing        *requestTiming
+	RedirectChain []string
+}
+
+// Dump writes a human-readable representation of the response, including
+// its body, timings, and redirect chain (whichever are available), to w.
+func (r *Response) Dump(w io.Writer) {
+	// Ensure to close previous body
+	b := r.Response.Body
+	defer b.Close()
+	r.Response.Body = io.NopCloser(bytes.NewReader(r.BodyBytes))
+	r.Response.Write(w)
+
+	if r.Timing != nil {
+		w.Write([]byte("\n"))
+		r.Timing.writeTo(w)
+	}
+
+	if len(r.RedirectChain) > 0 {
+		fmt.Fprintf(w, "\nRedirect chain:\n")
+		for i, u := range r.RedirectChain {
+			fmt.Fprintf(w, "  %d. %s\n", i+1, u)
+		}
+	}
+}
+
+// Runner performs HTTP requests against Client and evaluates Assertions
+// against the response, retrying according to Retry when the response
+// (or a transport error) matches a retryable condition.
+type Runner struct {
+	Client *http.Client
+	Retry  RetryConfig
+
+	// RedirectChain, if set, receives the sequence of redirect hop URLs
+	// recorded by the CheckRedirect callback installed by GetHTTPClient.
+	// Do resets it at the start of every attempt.
+	RedirectChain *[]string
+}
+
+// NewRunner returns a Runner that performs requests with client.
+func NewRunner(client *http.Client) *Runner {
+	return &Runner{Client: client}
+}
+
+// Do sends req (retrying per r.Retry) and evaluates assertions against the
+// response. It returns the last Response observed, even on failure, so
+// callers can render a dump, and a non-nil error describing why the call
+// failed.
+func (r *Runner) Do(ctx context.Context, req *http.Request, assertions ...Assertion) (*Response, error) {
+	if len(assertions) == 0 {
+		return nil, fmt.Errorf("no assertions defined")
+	}
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req = req.WithContext(ctx)
+
+	var lastRes *Response
+
+	for attempt := 0; ; attempt++ {
+		if r.RedirectChain != nil {
+			*r.RedirectChain = nil
+		}
+
+		attemptReq := req
+		if attempt > 0 {
+			attemptReq = req.Clone(ctx)
+			if req.GetBody != nil {
+				b, err := req.GetBody()
+				if err != nil {
+					return lastRes, fmt.Errorf("failed to rewind request body: %w", err)
+				}
+				attemptReq.Body = b
+			}
+		}
+
+		timing := newRequestTiming()
+		attemptReq = attemptReq.WithContext(httptrace.WithClientTrace(attemptReq.Context(), timing.trace()))
+
+		res, err := client.Do(attemptReq)
+		if err != nil {
+			if attempt < r.Retry.Retries && r.Retry.shouldRetry(err, nil, nil) {
+				time.Sleep(r.Retry.backoff(attempt + 1))
+				continue
+			}
+			return lastRes, fmt.Errorf("failed to send request: %w", err)
+		}
+		out := &Response{Response: res, Timing: timing}
+		if r.RedirectChain != nil {
+			out.RedirectChain = append([]string(nil), *r.RedirectChain...)
+		}
+		out.BodyBytes, _ = io.ReadAll(res.Body)
+		res.Body.Close()
+		// finish() must run after the body is fully read, not right after
+		// headers arrive, so Timing.Total covers the whole response and
+		// --assert-total-under actually differs from --assert-ttfb-under.
+		timing.finish()
+		lastRes = out
+
+		assertErr := runAssertions(out, assertions)
+
+		// A matching --retry-on-status is a retry trigger independent of
+		// whether any assertion failed, so it must be checked even when
+		// assertErr is nil.
+		if attempt < r.Retry.Retries && r.Retry.shouldRetry(nil, res, assertErr) {
+			delay := r.Retry.backoff(attempt + 1)
+			if d, ok := retryAfter(res.Header); ok {
+				delay = d
+			}
+			time.Sleep(delay)
+			continue
+		}
+
+		return out, assertErr
+	}
+}
+
+// AssertionResult is the outcome of evaluating a single Assertion against a
+// Response: Err is nil if the assertion passed.
+type AssertionResult struct {
+	Index int
+	Err   error
+}
+
+// EvaluateAssertions runs every assertion against res and returns the
+// per-assertion outcome, in the same order as assertions. It does not
+// perform a new request, so it is safe to call again (e.g. to build an
+// --output-junit report) after runAssertions has already run.
+func EvaluateAssertions(res *Response, assertions []Assertion) []AssertionResult {
+	results := make([]AssertionResult, len(assertions))
+	for i := range assertions {
+		results[i] = AssertionResult{Index: i, Err: assertions[i](res)}
+	}
+	return results
+}
+
+// runAssertions evaluates every assertion against res, returning an error
+// describing all failures, or nil if they all passed.
+func runAssertions(res *Response, assertions []Assertion) error {
+	results := EvaluateAssertions(res, assertions)
+
+	var assertErrors []error
+	for _, r := range results {
+		if r.Err != nil {
+			assertErrors = append(assertErrors, r.Err)
+		}
+	}
+	if len(assertErrors) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d assertions failed:\n", len(assertErrors))
+	for i := range assertErrors {
+		fmt.Fprintf(&b, "- %s\n", assertErrors[i])
+	}
+	return errors.New(b.String())
+}
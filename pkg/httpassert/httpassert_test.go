@@ -0,0 +1,37 @@
+package httpassert
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRunnerDoTimingIncludesBodyRead(t *testing.T) {
+	const bodyDelay = 50 * time.Millisecond
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher := w.(http.Flusher)
+		w.Write([]byte("a"))
+		flusher.Flush()
+		time.Sleep(bodyDelay)
+		w.Write([]byte("b"))
+	}))
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+
+	runner := NewRunner(srv.Client())
+	res, err := runner.Do(context.Background(), req, AssertStatusOK())
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	if res.Timing.Total < bodyDelay {
+		t.Fatalf("Timing.Total = %s, want >= %s (should include time spent reading the body)", res.Timing.Total, bodyDelay)
+	}
+}
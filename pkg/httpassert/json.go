@@ -0,0 +1,94 @@
+package httpassert
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/itchyny/gojq"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// AssertBodyJQ evaluates expr against the JSON response body using jq
+// semantics. If hasExpected is false, the first result must be truthy;
+// otherwise the first result must deep-equal expected.
+func AssertBodyJQ(expr string, expected interface{}, hasExpected bool) Assertion {
+	query, err := gojq.Parse(expr)
+	if err != nil {
+		return func(r *Response) error {
+			return fmt.Errorf("invalid jq expression %q: %s", expr, err)
+		}
+	}
+
+	return func(r *Response) error {
+		var input interface{}
+		if err := json.Unmarshal(r.BodyBytes, &input); err != nil {
+			return fmt.Errorf("body is not valid JSON: %s", err)
+		}
+
+		iter := query.Run(input)
+		v, ok := iter.Next()
+		if !ok {
+			return fmt.Errorf("jq expression %q produced no result", expr)
+		}
+		if err, ok := v.(error); ok {
+			return fmt.Errorf("jq expression %q failed: %s", expr, err)
+		}
+
+		if !hasExpected {
+			if isTruthy(v) {
+				return nil
+			}
+			return fmt.Errorf("jq expression %q evaluated to %v, want a truthy value", expr, v)
+		}
+
+		// gojq yields Go ints for computed numbers (e.g. length) while
+		// encoding/json decodes expected numbers as float64, so round-trip
+		// v through JSON to compare both in the same representation.
+		if b, err := json.Marshal(v); err == nil {
+			var norm interface{}
+			if err := json.Unmarshal(b, &norm); err == nil {
+				v = norm
+			}
+		}
+		if !reflect.DeepEqual(v, expected) {
+			return fmt.Errorf("jq expression %q evaluated to %v, want %v", expr, v, expected)
+		}
+		return nil
+	}
+}
+
+func isTruthy(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	default:
+		return true
+	}
+}
+
+// AssertBodyJSONSchema validates the response body against the JSON Schema
+// (draft-7) document loaded from schemaFile.
+func AssertBodyJSONSchema(schemaFile string) Assertion {
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft7
+	schema, err := compiler.Compile(schemaFile)
+	if err != nil {
+		return func(r *Response) error {
+			return fmt.Errorf("cannot compile JSON schema %s: %s", schemaFile, err)
+		}
+	}
+
+	return func(r *Response) error {
+		var doc interface{}
+		if err := json.Unmarshal(r.BodyBytes, &doc); err != nil {
+			return fmt.Errorf("body is not valid JSON: %s", err)
+		}
+		if err := schema.Validate(doc); err != nil {
+			return fmt.Errorf("body does not match schema %s: %s", schemaFile, err)
+		}
+		return nil
+	}
+}
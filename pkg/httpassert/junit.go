@@ -0,0 +1,53 @@
+package httpassert
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name    string        `xml:"name,attr"`
+	Failure *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+// WriteJUnit serializes the outcome of each assertion in results as a JUnit
+// XML report, with a <failure> element carrying the assertion error and a
+// full dump of res for any testcase that failed.
+func WriteJUnit(w io.Writer, res *Response, results []AssertionResult) error {
+	var dump strings.Builder
+	if res != nil {
+		res.Dump(&dump)
+	}
+
+	suite := junitTestSuite{Name: "http-assert", Tests: len(results)}
+	for _, r := range results {
+		tc := junitTestCase{Name: fmt.Sprintf("assertion-%d", r.Index+1)}
+		if r.Err != nil {
+			suite.Failures++
+			tc.Failure = &junitFailure{Message: r.Err.Error(), Content: dump.String()}
+		}
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(suite)
+}
@@ -0,0 +1,58 @@
+package httpassert
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	res := &Response{
+		Response: &http.Response{
+			StatusCode: 200,
+			Proto:      "HTTP/1.1",
+			Header:     http.Header{},
+			Body:       http.NoBody,
+		},
+	}
+	results := []AssertionResult{
+		{Index: 0, Err: nil},
+		{Index: 1, Err: errors.New("status 200 does not equal 404")},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, res, results); err != nil {
+		t.Fatalf("WriteJUnit() error = %v", err)
+	}
+
+	var suite junitTestSuite
+	if err := xml.Unmarshal(buf.Bytes(), &suite); err != nil {
+		t.Fatalf("WriteJUnit() produced invalid XML: %v", err)
+	}
+
+	if suite.Name != "http-assert" {
+		t.Errorf("suite.Name = %q, want %q", suite.Name, "http-assert")
+	}
+	if suite.Tests != 2 {
+		t.Errorf("suite.Tests = %d, want 2", suite.Tests)
+	}
+	if suite.Failures != 1 {
+		t.Errorf("suite.Failures = %d, want 1", suite.Failures)
+	}
+	if len(suite.TestCases) != 2 {
+		t.Fatalf("len(suite.TestCases) = %d, want 2", len(suite.TestCases))
+	}
+
+	pass, fail := suite.TestCases[0], suite.TestCases[1]
+	if pass.Name != "assertion-1" || pass.Failure != nil {
+		t.Errorf("TestCases[0] = %+v, want passing assertion-1", pass)
+	}
+	if fail.Name != "assertion-2" || fail.Failure == nil {
+		t.Fatalf("TestCases[1] = %+v, want failing assertion-2", fail)
+	}
+	if fail.Failure.Message != "status 200 does not equal 404" {
+		t.Errorf("Failure.Message = %q, want %q", fail.Failure.Message, "status 200 does not equal 404")
+	}
+}
@@ -0,0 +1,58 @@
+package httpassert
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// AssertRedirectChain asserts that the sequence of redirect hops followed
+// while handling the request matches the provided ordered list. Each want
+// entry is either an exact URL or a /regexp/ pattern.
+func AssertRedirectChain(want []string) Assertion {
+	return func(r *Response) error {
+		got := r.RedirectChain
+		if len(got) != len(want) {
+			return fmt.Errorf("redirect chain has %d hop(s) %v, want %d hop(s) %v", len(got), got, len(want), want)
+		}
+		for i, w := range want {
+			if err := matchRedirectHop(w, got[i]); err != nil {
+				return fmt.Errorf("redirect hop %d: %s", i+1, err)
+			}
+		}
+		return nil
+	}
+}
+
+func matchRedirectHop(want, got string) error {
+	if strings.HasPrefix(want, "/") && strings.HasSuffix(want, "/") && len(want) > 1 {
+		re, err := regexp.Compile(want[1 : len(want)-1])
+		if err != nil {
+			return fmt.Errorf("invalid regexp %q: %s", want, err)
+		}
+		if !re.MatchString(got) {
+			return fmt.Errorf("%q does not match %s", got, want)
+		}
+		return nil
+	}
+
+	if want != got {
+		return fmt.Errorf("%q does not equal %q", got, want)
+	}
+	return nil
+}
+
+// AssertFinalURL asserts that the URL reached at the end of the redirect
+// chain (or the request URL itself, if no redirects occurred) equals want.
+func AssertFinalURL(want string) Assertion {
+	return func(r *Response) error {
+		got := r.Request.URL.String()
+		if len(r.RedirectChain) > 0 {
+			got = r.RedirectChain[len(r.RedirectChain)-1]
+		}
+		if got != want {
+			return fmt.Errorf("final URL %q does not equal %q", got, want)
+		}
+		return nil
+	}
+}
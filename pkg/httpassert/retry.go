@@ -0,0 +1,69 @@
+package httpassert
+
+import (
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryConfig controls how Runner.Do retries a failing request.
+type RetryConfig struct {
+	Retries  int
+	Delay    time.Duration
+	MaxDelay time.Duration
+	OnStatus map[int]bool
+	OnFail   bool
+}
+
+// backoff returns the delay to wait before the given attempt (1-indexed),
+// using exponential backoff with +/-20% jitter, capped at cfg.MaxDelay.
+func (cfg RetryConfig) backoff(attempt int) time.Duration {
+	d := time.Duration(float64(cfg.Delay) * math.Pow(2, float64(attempt-1)))
+	if d > cfg.MaxDelay {
+		d = cfg.MaxDelay
+	}
+
+	jitter := 0.8 + rand.Float64()*0.4
+	d = time.Duration(float64(d) * jitter)
+	if d > cfg.MaxDelay {
+		d = cfg.MaxDelay
+	}
+	return d
+}
+
+// retryAfter parses a Retry-After header value, returning the delay it
+// specifies and whether one was present.
+func retryAfter(h http.Header) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t), true
+	}
+
+	return 0, false
+}
+
+// shouldRetry reports whether a request attempt should be retried given the
+// network error (if any), the response (if any), and the assertion error
+// from the completed attempt (if any).
+func (cfg RetryConfig) shouldRetry(err error, res *http.Response, assertErr error) bool {
+	if err != nil {
+		return true
+	}
+	if res != nil && cfg.OnStatus[res.StatusCode] {
+		return true
+	}
+	if cfg.OnFail && assertErr != nil {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package httpassert
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+var errTest = errors.New("assertion failed")
+
+func TestRetryConfigBackoff(t *testing.T) {
+	cfg := RetryConfig{Delay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	tests := []struct {
+		attempt  int
+		wantBase time.Duration
+	}{
+		{attempt: 1, wantBase: 100 * time.Millisecond},
+		{attempt: 2, wantBase: 200 * time.Millisecond},
+		{attempt: 3, wantBase: 400 * time.Millisecond},
+		{attempt: 5, wantBase: cfg.MaxDelay}, // 1.6s uncapped, capped to MaxDelay
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			got := cfg.backoff(tt.attempt)
+			if got > cfg.MaxDelay {
+				t.Fatalf("backoff(%d) = %s exceeds MaxDelay %s", tt.attempt, got, cfg.MaxDelay)
+			}
+
+			lo := time.Duration(float64(tt.wantBase) * 0.8)
+			hi := time.Duration(float64(tt.wantBase) * 1.2)
+			if tt.wantBase == cfg.MaxDelay {
+				lo = 0
+				hi = cfg.MaxDelay
+			}
+			if got < lo || got > hi {
+				t.Fatalf("backoff(%d) = %s, want in [%s, %s]", tt.attempt, got, lo, hi)
+			}
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		wantOK  bool
+		wantDur time.Duration
+	}{
+		{name: "absent", header: "", wantOK: false},
+		{name: "seconds", header: "120", wantOK: true, wantDur: 120 * time.Second},
+		{name: "http-date", header: time.Now().UTC().Add(30 * time.Second).Format(http.TimeFormat), wantOK: true, wantDur: 30 * time.Second},
+		{name: "garbage", header: "not-a-value", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			if tt.header != "" {
+				h.Set("Retry-After", tt.header)
+			}
+
+			got, ok := retryAfter(h)
+			if ok != tt.wantOK {
+				t.Fatalf("retryAfter() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+
+			delta := got - tt.wantDur
+			if delta < 0 {
+				delta = -delta
+			}
+			if delta > time.Second {
+				t.Fatalf("retryAfter() = %s, want ~%s", got, tt.wantDur)
+			}
+		})
+	}
+}
+
+func TestRetryConfigShouldRetry(t *testing.T) {
+	cfg := RetryConfig{OnStatus: map[int]bool{503: true}, OnFail: true}
+
+	tests := []struct {
+		name      string
+		err       error
+		status    int
+		assertErr error
+		want      bool
+	}{
+		{name: "network error always retries", err: http.ErrHandlerTimeout, want: true},
+		{name: "matching status retries even without assertion failure", status: 503, want: true},
+		{name: "non-matching status without assertion failure does not retry", status: 200, want: false},
+		{name: "assertion failure retries when OnFail is set", status: 200, assertErr: errTest, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res *http.Response
+			if tt.status != 0 {
+				res = &http.Response{StatusCode: tt.status}
+			}
+
+			got := cfg.shouldRetry(tt.err, res, tt.assertErr)
+			if got != tt.want {
+				t.Fatalf("shouldRetry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
@@ -0,0 +1,132 @@
+package httpassert
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http/httptrace"
+	"time"
+)
+
+// requestTiming accumulates the phase durations captured by an
+// httptrace.ClientTrace attached to a single request attempt.
+type requestTiming struct {
+	start        time.Time
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+
+	DNSDuration     time.Duration
+	ConnectDuration time.Duration
+	TLSDuration     time.Duration
+	TTFB            time.Duration
+	Total           time.Duration
+	TLSVersion      uint16
+}
+
+func newRequestTiming() *requestTiming {
+	return &requestTiming{start: time.Now()}
+}
+
+// trace returns an httptrace.ClientTrace that records phase timestamps into
+// t as the request progresses.
+func (t *requestTiming) trace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { t.dnsStart = time.Now() },
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			if !t.dnsStart.IsZero() {
+				t.DNSDuration = time.Since(t.dnsStart)
+			}
+		},
+		ConnectStart: func(string, string) { t.connectStart = time.Now() },
+		ConnectDone: func(network, addr string, err error) {
+			if !t.connectStart.IsZero() {
+				t.ConnectDuration = time.Since(t.connectStart)
+			}
+		},
+		TLSHandshakeStart: func() { t.tlsStart = time.Now() },
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			if !t.tlsStart.IsZero() {
+				t.TLSDuration = time.Since(t.tlsStart)
+			}
+			t.TLSVersion = state.Version
+		},
+		GotFirstResponseByte: func() {
+			t.TTFB = time.Since(t.start)
+		},
+	}
+}
+
+func (t *requestTiming) finish() {
+	t.Total = time.Since(t.start)
+}
+
+func (t *requestTiming) writeTo(w io.Writer) {
+	fmt.Fprintf(w, "Timing:\n")
+	fmt.Fprintf(w, "  dns:     %s\n", t.DNSDuration)
+	fmt.Fprintf(w, "  connect: %s\n", t.ConnectDuration)
+	fmt.Fprintf(w, "  tls:     %s\n", t.TLSDuration)
+	fmt.Fprintf(w, "  ttfb:    %s\n", t.TTFB)
+	fmt.Fprintf(w, "  total:   %s\n", t.Total)
+}
+
+var tlsVersionNames = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+func tlsVersionName(v uint16) string {
+	for name, ver := range tlsVersionNames {
+		if ver == v {
+			return name
+		}
+	}
+	return fmt.Sprintf("0x%x", v)
+}
+
+// AssertTTFBUnder asserts that the time to first response byte is under d.
+func AssertTTFBUnder(d time.Duration) Assertion {
+	return func(r *Response) error {
+		if r.Timing == nil {
+			return fmt.Errorf("no timing information available")
+		}
+		if r.Timing.TTFB > d {
+			return fmt.Errorf("time to first byte %s exceeds %s", r.Timing.TTFB, d)
+		}
+		return nil
+	}
+}
+
+// AssertTotalUnder asserts that the total request duration is under d.
+func AssertTotalUnder(d time.Duration) Assertion {
+	return func(r *Response) error {
+		if r.Timing == nil {
+			return fmt.Errorf("no timing information available")
+		}
+		if r.Timing.Total > d {
+			return fmt.Errorf("total response time %s exceeds %s", r.Timing.Total, d)
+		}
+		return nil
+	}
+}
+
+// AssertTLSVersion asserts that the negotiated TLS version equals the
+// provided value ("1.2" or "1.3").
+func AssertTLSVersion(v string) Assertion {
+	want, ok := tlsVersionNames[v]
+	if !ok {
+		return func(r *Response) error {
+			return fmt.Errorf("unsupported value for --assert-tls-version: %q, want 1.2 or 1.3", v)
+		}
+	}
+
+	return func(r *Response) error {
+		if r.Timing == nil || r.Timing.TLSVersion == 0 {
+			return fmt.Errorf("connection did not use TLS")
+		}
+		if r.Timing.TLSVersion != want {
+			return fmt.Errorf("TLS version %s does not match expected %s", tlsVersionName(r.Timing.TLSVersion), v)
+		}
+		return nil
+	}
+}
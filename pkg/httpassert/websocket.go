@@ -0,0 +1,119 @@
+package httpassert
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// GetWebSocketDialer builds a *websocket.Dialer that applies the same
+// --maphost overrides used for plain HTTP requests.
+func GetWebSocketDialer(hostMappings []HostMapping) *websocket.Dialer {
+	return &websocket.Dialer{
+		NetDialContext:   dialContext(hostMappings),
+		HandshakeTimeout: 10 * time.Second,
+	}
+}
+
+// WebSocketResult is the outcome of a WebSocket probe performed by DialWebSocket:
+// the negotiated subprotocol, an optional echoed message, and the close code
+// the server sent when the connection was torn down.
+type WebSocketResult struct {
+	Subprotocol string
+	RecvMessage string
+	CloseCode   int
+}
+
+// readTimeout bounds how long DialWebSocket will wait for a reply or a close
+// frame, so a server that never pushes anything can't hang the probe (and
+// the CI job running it) indefinitely.
+const readTimeout = 10 * time.Second
+
+// DialWebSocket performs the HTTP Upgrade handshake against url using dialer
+// (which should carry the same --maphost DialContext override used for
+// plain HTTP requests), optionally sends sendMsg once connected, and reads
+// back a single message. It also waits for the close frame when
+// wantCloseCode is set, so --assert-ws-close-code can observe it.
+func DialWebSocket(dialer *websocket.Dialer, url string, header map[string][]string, sendMsg string, wantCloseCode bool) (*WebSocketResult, error) {
+	conn, res, err := dialer.Dial(url, header)
+	if err != nil {
+		return nil, fmt.Errorf("websocket handshake failed: %w", err)
+	}
+	defer conn.Close()
+
+	result := &WebSocketResult{Subprotocol: res.Header.Get("Sec-WebSocket-Protocol")}
+
+	conn.SetCloseHandler(func(code int, text string) error {
+		result.CloseCode = code
+		return nil
+	})
+
+	if sendMsg != "" {
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(sendMsg)); err != nil {
+			return result, fmt.Errorf("failed to send websocket message: %w", err)
+		}
+	}
+
+	if sendMsg == "" && !wantCloseCode {
+		return result, nil
+	}
+
+	// Read at least once for the --assert-ws-send reply, and keep reading
+	// until the connection closes when --assert-ws-close-code was
+	// requested, so it works standalone even when no message is sent.
+	for {
+		conn.SetReadDeadline(time.Now().Add(readTimeout))
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			if _, ok := err.(*websocket.CloseError); ok || result.CloseCode != 0 {
+				return result, nil
+			}
+			return result, fmt.Errorf("failed to read websocket message: %w", err)
+		}
+		if result.RecvMessage == "" {
+			result.RecvMessage = string(msg)
+		}
+		if !wantCloseCode {
+			return result, nil
+		}
+	}
+}
+
+// AssertWSSubprotocol asserts that the server negotiated the given
+// WebSocket subprotocol.
+func AssertWSSubprotocol(want string) func(*WebSocketResult) error {
+	return func(r *WebSocketResult) error {
+		if r.Subprotocol != want {
+			return fmt.Errorf("websocket subprotocol %q does not equal %q", r.Subprotocol, want)
+		}
+		return nil
+	}
+}
+
+// AssertWSRecvMatch asserts that the message read back after --assert-ws-send
+// matches the provided regexp.
+func AssertWSRecvMatch(pattern string) func(*WebSocketResult) error {
+	re, err := regexp.Compile(pattern)
+	return func(r *WebSocketResult) error {
+		if err != nil {
+			return fmt.Errorf("invalid regexp %q: %s", pattern, err)
+		}
+		if !re.MatchString(r.RecvMessage) {
+			return fmt.Errorf("websocket message %q does not match %s", r.RecvMessage, pattern)
+		}
+		return nil
+	}
+}
+
+// AssertWSCloseCode asserts that the server closed the connection with the
+// given close code.
+func AssertWSCloseCode(want int) func(*WebSocketResult) error {
+	return func(r *WebSocketResult) error {
+		if r.CloseCode != want {
+			return fmt.Errorf("websocket close code %d does not equal %d", r.CloseCode, want)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,49 @@
+package httpassert
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+var upgrader = websocket.Upgrader{}
+
+// idleWSServer upgrades every connection and then does nothing: it never
+// sends a message and never closes the connection on its own.
+func idleWSServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("server upgrade failed: %v", err)
+			return
+		}
+		defer conn.Close()
+		time.Sleep(time.Second)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestDialWebSocketDoesNotHangWithoutCloseCodeAssertion(t *testing.T) {
+	srv := idleWSServer(t)
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := DialWebSocket(&websocket.Dialer{}, url, nil, "", false); err != nil {
+			t.Errorf("DialWebSocket() error = %v", err)
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("DialWebSocket() did not return for an idle server when --assert-ws-close-code was not requested")
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PlanitarInc/http-assert/pkg/httpassert"
+)
+
+func registerRetryFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("retry", 0, "Number of times to retry the request on failure")
+	cmd.Flags().Duration("retry-delay", time.Second, "Base delay between retries, doubled on each subsequent attempt")
+	cmd.Flags().Duration("retry-max-delay", 30*time.Second, "Maximum delay between retries")
+	cmd.Flags().StringArray("retry-on-status", nil, "Retry when the response status equals one of the provided codes; may be repeated")
+	cmd.Flags().Bool("retry-on-fail", false, "Retry when any assertion fails, not just on network errors or --retry-on-status matches")
+}
+
+func parseRetryFlags(cmd *cobra.Command) httpassert.RetryConfig {
+	retries, _ := cmd.Flags().GetInt("retry")
+	delay, _ := cmd.Flags().GetDuration("retry-delay")
+	maxDelay, _ := cmd.Flags().GetDuration("retry-max-delay")
+	onFail, _ := cmd.Flags().GetBool("retry-on-fail")
+
+	onStatus := map[int]bool{}
+	codes, _ := cmd.Flags().GetStringArray("retry-on-status")
+	for _, c := range codes {
+		s, err := strconv.Atoi(strings.TrimSpace(c))
+		if err != nil {
+			die(91, "Invalid value for --retry-on-status flag: %q", c)
+		}
+		onStatus[s] = true
+	}
+
+	return httpassert.RetryConfig{
+		Retries:  retries,
+		Delay:    delay,
+		MaxDelay: maxDelay,
+		OnStatus: onStatus,
+		OnFail:   onFail,
+	}
+}
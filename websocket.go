@@ -0,0 +1,61 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PlanitarInc/http-assert/pkg/httpassert"
+)
+
+func registerWebSocketFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("websocket", false, "Perform a WebSocket Upgrade handshake instead of a plain HTTP request")
+	cmd.Flags().String("assert-ws-subprotocol", "", "Assert the negotiated WebSocket subprotocol equals the provided value")
+	cmd.Flags().String("assert-ws-send", "", "Send the provided message once the WebSocket handshake completes")
+	cmd.Flags().String("assert-ws-recv", "", "Assert the message received in reply to --assert-ws-send matches the provided regexp")
+	cmd.Flags().Int("assert-ws-close-code", 0, "Assert the WebSocket close frame carries the provided code")
+}
+
+// runWebSocket performs the --websocket probe and its assertions, dying on
+// failure like the plain HTTP path does.
+func runWebSocket(cmd *cobra.Command, url string) {
+	dialer := httpassert.GetWebSocketDialer(parseHostMappings(cmd))
+
+	header := http.Header{}
+	applyAuthFlags(cmd, &http.Request{Header: header})
+
+	sendMsg, _ := cmd.Flags().GetString("assert-ws-send")
+	wantCloseCode := cmd.Flags().Changed("assert-ws-close-code")
+	result, err := httpassert.DialWebSocket(dialer, url, header, sendMsg, wantCloseCode)
+	if err != nil {
+		die(93, "%s", err)
+	}
+
+	var failures []string
+
+	if cmd.Flags().Changed("assert-ws-subprotocol") {
+		v, _ := cmd.Flags().GetString("assert-ws-subprotocol")
+		if err := httpassert.AssertWSSubprotocol(v)(result); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+
+	if cmd.Flags().Changed("assert-ws-recv") {
+		v, _ := cmd.Flags().GetString("assert-ws-recv")
+		if err := httpassert.AssertWSRecvMatch(v)(result); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+
+	if cmd.Flags().Changed("assert-ws-close-code") {
+		v, _ := cmd.Flags().GetInt("assert-ws-close-code")
+		if err := httpassert.AssertWSCloseCode(v)(result); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+
+	if len(failures) > 0 {
+		die(93, "%d assertions failed:\n- %s", len(failures), strings.Join(failures, "\n- "))
+	}
+}